@@ -1,6 +1,8 @@
 package module
 
 import (
+	"context"
+
 	"fantastic-broccoli/properties"
 	"go.uber.org/zap"
 )
@@ -9,11 +11,15 @@ import (
 type Module interface {
 	Start(queue *NotificationQueue, logger *zap.Logger) error
 	Configure(properties *properties.Properties) error
-	Process() error
-	Stop() error
 
-	StartSession() error
-	StopSession() error
+	// Serve runs the module's processing loop until ctx is cancelled. It
+	// replaces the former Process/Stop pair: implementations should return
+	// once ctx.Done() fires, after unwinding any in-flight work, and nil
+	// error in that case is treated as a graceful stop rather than a failure.
+	Serve(ctx context.Context) error
+
+	StartSession(ctx context.Context) error
+	StopSession(ctx context.Context) error
 
 	Name() string
 	State() int
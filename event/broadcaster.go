@@ -0,0 +1,36 @@
+package event
+
+import "context"
+
+// Broadcaster multiplexes a single producer channel to every subscriber
+// currently registered on the bus for a pattern, without requiring the
+// producer to know who (or how many) is listening. It is a thin
+// convenience wrapper over Bus.Publish/SubscribePattern for producers that
+// already own a channel of values, such as a sensor's read loop.
+type Broadcaster struct {
+	bus     *Bus
+	pattern string
+}
+
+// NewBroadcaster returns a Broadcaster that republishes everything read
+// from the channel given to Serve onto pattern.
+func (bus *Bus) NewBroadcaster(pattern string) *Broadcaster {
+	return &Broadcaster{bus: bus, pattern: pattern}
+}
+
+// Serve drains source, publishing each value to every subscriber currently
+// matching the broadcaster's pattern, until source is closed or ctx is
+// cancelled.
+func (b *Broadcaster) Serve(ctx context.Context, source <-chan interface{}) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case value, ok := <-source:
+			if !ok {
+				return
+			}
+			b.bus.Publish(ctx, b.pattern, value, nil)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package event
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"reflect"
 	"runtime"
@@ -13,6 +14,7 @@ import (
 
 type Event struct {
 	payload interface{}
+	seq     uint64
 	reply   chan interface{}
 	error   chan error
 }
@@ -22,15 +24,38 @@ type subscriber struct {
 	cancel func()
 }
 
+// Bus dispatches events published on a channel to every subscriber whose
+// pattern matches it. Patterns are `.`-separated, with `*` matching exactly
+// one segment and `#` matching the remainder of the channel (e.g.
+// `module.*.data` or `service.network.#`), and are stored in a trie so a
+// Publish's lookup cost stays O(depth) regardless of how many subscribers
+// are registered.
 type Bus struct {
-	subscribers map[string][]subscriber
+	subscribers *trieNode
 	ids         map[string]interface{}
 	sync        sync.Mutex
+
+	ring           *ringLog
+	store          EventStore
+	strictChannels bool
 }
 type EventConsumer func(event *Event, err error)
 
-const publishTimeout = 25 * time.Millisecond
-const replyTimeout = 25 * time.Millisecond
+// NewBus creates a Bus, optionally keeping a ring buffer and/or durable
+// store of published events so late or reconnecting subscribers can
+// replay what they missed via FromSequence.
+func NewBus(opts ...Option) *Bus {
+	bus := &Bus{ids: make(map[string]interface{}), subscribers: newTrieNode()}
+	for _, opt := range opts {
+		opt(bus)
+	}
+	return bus
+}
+
+// defaultPublishTimeout is used when the caller's context carries no
+// deadline, preserving today's behaviour for callers not yet updated to
+// pass one.
+const defaultPublishTimeout = 25 * time.Millisecond
 
 var ErrPublishTimeout = errors.New("publish timeout")
 var ErrChannelNotFound = errors.New("channel not found")
@@ -41,38 +66,257 @@ var ErrChannelSubscriberAlreadyExists = errors.New("channel subscriber already e
 func (event *Event) Message() interface{}      { return event.payload }
 func (event *Event) Reply() chan<- interface{} { return event.reply }
 
-func (bus *Bus) Publish(channel string, data interface{}, handler ReplyHandler) {
-	if _, exists := bus.subscribers[channel]; !exists {
+// Seq is the sequence number this event was recorded under, when the bus
+// was built with WithRingBuffer and/or WithPersistence; it is the value a
+// subscriber should later pass to FromSequence to resume from here. It is
+// zero when neither option is configured.
+func (event *Event) Seq() uint64 { return event.seq }
+
+// deadlineOf returns the duration remaining until ctx's deadline, falling
+// back to defaultPublishTimeout when ctx carries none.
+func deadlineOf(ctx context.Context) time.Duration {
+	if deadline, ok := ctx.Deadline(); ok {
+		return time.Until(deadline)
+	}
+	return defaultPublishTimeout
+}
+
+func (bus *Bus) tree() *trieNode {
+	if bus.subscribers == nil {
+		bus.subscribers = newTrieNode()
+	}
+	return bus.subscribers
+}
+
+// record appends data to the ring buffer and/or persistence store, if
+// configured, and returns the sequence number it was assigned so it can be
+// attached to the Event delivered to subscribers. It is a no-op, returning
+// 0, when neither is configured. When both are configured, the persistence
+// store's sequence is authoritative since it is the durable one, and the
+// ring buffer's entry is recorded under that same sequence number (via
+// appendAt) rather than its own counter, so a seq obtained from one source
+// can be passed to FromSequence and mean the same thing against the other.
+//
+// Callers must hold bus.sync: record runs in lock-step with subscribe's
+// replay-then-insert so an event can never land in the gap between a new
+// subscriber's replay snapshot and it becoming visible to Publish.
+func (bus *Bus) record(channel string, data interface{}) uint64 {
+	if bus.ring == nil && bus.store == nil {
+		return 0
+	}
+	ts := time.Now()
+
+	if bus.store == nil {
+		return bus.ring.append(channel, data, ts)
+	}
+
+	payload, err := json.Marshal(data)
+	if err != nil {
+		if bus.ring != nil {
+			return bus.ring.append(channel, data, ts)
+		}
+		return 0
+	}
+
+	seq, err := bus.store.Append(channel, payload, ts)
+	if err != nil {
+		if bus.ring != nil {
+			return bus.ring.append(channel, data, ts)
+		}
+		return 0
+	}
+
+	if bus.ring != nil {
+		bus.ring.appendAt(channel, seq, data, ts)
+	}
+	return seq
+}
+
+func (bus *Bus) Publish(ctx context.Context, channel string, data interface{}, handler ReplyHandler) {
+	bus.sync.Lock()
+	seq := bus.record(channel, data)
+	matched := bus.tree().match(channel)
+	bus.sync.Unlock()
+
+	if len(matched) == 0 {
 		if handler != nil {
 			handler.consume(nil, nil, ErrChannelNotFound, 0)
 		}
 		return
 	}
 
+	timeout := deadlineOf(ctx)
+
 	bus.sync.Lock()
 	defer bus.sync.Unlock()
 
-	for _, evChannel := range bus.subscribers[channel] {
-		event := &Event{payload: data, reply: make(chan interface{}), error: make(chan error)}
+	for _, evChannel := range matched {
+		event := &Event{payload: data, seq: seq, reply: make(chan interface{}), error: make(chan error)}
 		go func(ch chan<- *Event, e *Event) {
 			select {
 			case ch <- e:
-			case <-time.After(replyTimeout):
+			case <-ctx.Done():
+				event.error <- ctx.Err()
+			case <-time.After(timeout):
 				event.error <- ErrPublishTimeout
 			}
 		}(evChannel.ch, event)
 
 		if handler != nil {
-			handler.consume(event.reply, event.error, nil, replyTimeout)
+			handler.consume(event.reply, event.error, nil, timeout)
 		}
 	}
 }
 
+// PublishSync publishes data on channel and blocks until every matching
+// subscriber has replied (or failed to within ctx's deadline), returning
+// the aggregated replies in the order the trie matched their subscribers
+// (`#` patterns, then `*` patterns, then exact segments; subscribers of one
+// pattern in subscription order) instead of driving a single ReplyHandler.
+func (bus *Bus) PublishSync(ctx context.Context, channel string, data interface{}) ([]interface{}, error) {
+	bus.sync.Lock()
+	seq := bus.record(channel, data)
+	matched := bus.tree().match(channel)
+	bus.sync.Unlock()
+
+	if len(matched) == 0 {
+		return nil, ErrChannelNotFound
+	}
+
+	timeout := deadlineOf(ctx)
+	replies := make([]interface{}, 0, len(matched))
+	var failures []error
+
+	for _, evChannel := range matched {
+		event := &Event{payload: data, seq: seq, reply: make(chan interface{}, 1), error: make(chan error, 1)}
+
+		select {
+		case evChannel.ch <- event:
+		case <-ctx.Done():
+			return replies, ctx.Err()
+		case <-time.After(timeout):
+			failures = append(failures, ErrPublishTimeout)
+			continue
+		}
+
+		select {
+		case reply := <-event.reply:
+			replies = append(replies, reply)
+		case err := <-event.error:
+			failures = append(failures, err)
+		case <-ctx.Done():
+			return replies, ctx.Err()
+		case <-time.After(timeout):
+			failures = append(failures, ErrPublishTimeout)
+		}
+	}
+
+	if len(failures) > 0 {
+		return replies, errors.Errorf("%d/%d subscribers failed to reply: %v", len(failures), len(matched), failures)
+	}
+	return replies, nil
+}
+
+// Subscribe registers handler on the exact channel given; it is equivalent
+// to calling SubscribePattern with a pattern containing no `*`/`#` segment.
+//
 // TODO: Manage already exists
-func (bus *Bus) Subscribe(channel string, handler EventConsumer) error {
+func (bus *Bus) Subscribe(channel string, handler EventConsumer, opts ...SubscribeOption) error {
+	return bus.subscribe(channel, handler, opts...)
+}
+
+// SubscribePattern registers handler on every channel matching pattern, a
+// `.`-separated topic where `*` matches exactly one segment and `#` matches
+// the remainder of the channel (e.g. `module.*.data`, `service.network.#`).
+func (bus *Bus) SubscribePattern(pattern string, handler EventConsumer, opts ...SubscribeOption) error {
+	return bus.subscribe(pattern, handler, opts...)
+}
+
+// replay delivers every event recorded for channel after cfg.fromSequence,
+// oldest first and each seq once: events older than the ring buffer's
+// oldest retained entry come from the persistence store, the rest from the
+// ring buffer, each payload carrying the Seq it was recorded under so the subscriber
+// can pass it straight back into a later FromSequence to resume again. The
+// ring buffer keeps the original typed payload, but the persistence store
+// only ever has the JSON bytes record() wrote, so those are decoded back
+// into a generic value first - a handler must see the same shape of
+// payload whether the event was replayed or delivered live.
+//
+// Callers must hold bus.sync for the whole replay-then-insert sequence:
+// replaying the store/ring snapshot and becoming visible to Publish have
+// to happen atomically, or an event recorded in between would be neither
+// part of the replay nor delivered live.
+func (bus *Bus) replay(channel string, cfg *subscribeConfig, handler EventConsumer) {
+	if !cfg.replay {
+		return
+	}
+
+	var recent []ringEntry
+	if bus.ring != nil {
+		recent = bus.ring.since(channel, cfg.fromSequence)
+	}
+
+	if bus.store != nil {
+		if it, err := bus.store.Read(channel, cfg.fromSequence); err == nil {
+			bus.replayStore(it, recent, handler)
+		}
+	}
+
+	for _, e := range recent {
+		handler(&Event{payload: e.payload, seq: e.seq}, nil)
+	}
+}
+
+// replayStore delivers the store's records up to, but not including, the
+// first seq still held in recent, which replay delivers from the ring.
+func (bus *Bus) replayStore(it EventIterator, recent []ringEntry, handler EventConsumer) {
+	defer it.Close()
+
+	for it.Next() {
+		record := it.Record()
+		if len(recent) > 0 && record.Seq >= recent[0].seq {
+			return
+		}
+
+		var payload interface{}
+		if err := json.Unmarshal(record.Payload, &payload); err != nil {
+			payload = record.Payload
+		}
+		handler(&Event{payload: payload, seq: record.Seq}, nil)
+	}
+}
+
+func (bus *Bus) subscribe(pattern string, handler EventConsumer, opts ...SubscribeOption) error {
+	cfg := &subscribeConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
 	ch := make(chan *Event)
 	ctx, cnl := context.WithCancel(context.Background())
 
+	id := fmt.Sprintf("%s:%s", pattern, runtime.FuncForPC(reflect.ValueOf(handler).Pointer()).Name())
+
+	bus.sync.Lock()
+	if _, exists := bus.ids[id]; exists {
+		bus.sync.Unlock()
+		cnl()
+		return ErrChannelSubscriberAlreadyExists
+	}
+
+	// Replay and trie-insertion happen under the same lock as
+	// Publish/PublishSync's record+match, closing the gap where a
+	// concurrently published event would otherwise be neither part of the
+	// replay snapshot nor delivered live.
+	bus.replay(pattern, cfg, handler)
+
+	if bus.ids == nil {
+		bus.ids = make(map[string]interface{})
+	}
+	bus.tree().insert(pattern, subscriber{id: id, ch: ch, cancel: cnl})
+	bus.ids[id] = nil
+	bus.sync.Unlock()
+
 	go func(channel <-chan *Event, ctx context.Context) {
 		defer handler(nil, ErrChannelClosed)
 
@@ -90,41 +334,27 @@ func (bus *Bus) Subscribe(channel string, handler EventConsumer) error {
 		}
 	}(ch, ctx)
 
+	return nil
+}
+
+func (bus *Bus) Unsubscribe(channel string, handler EventConsumer) error {
 	id := fmt.Sprintf("%s:%s", channel, runtime.FuncForPC(reflect.ValueOf(handler).Pointer()).Name())
 
 	bus.sync.Lock()
 	defer bus.sync.Unlock()
 
-	if _, exists := bus.ids[id]; exists {
-		return ErrChannelSubscriberAlreadyExists
+	if _, exists := bus.ids[id]; !exists {
+		return ErrChannelSubscriberNotFound
 	}
 
-	bus.subscribers[channel] = append(bus.subscribers[channel], subscriber{id: id, ch: ch, cancel: cnl})
-	bus.ids[id] = nil
-	return nil
-}
-
-func (bus *Bus) Unsubscribe(channel string, handler EventConsumer) error {
-	if sub, exists := bus.subscribers[channel]; !exists {
-		return ErrChannelNotFound
-	} else {
-		id := fmt.Sprintf("%s:%s", channel, runtime.FuncForPC(reflect.ValueOf(handler).Pointer()).Name())
-
-		for i, sbcr := range sub {
-			if sbcr.id == id {
-				bus.sync.Lock()
-				defer bus.sync.Unlock()
-				sbcr.cancel()
-				sub = append(sub[:i], sub[i+1:]...)
-				delete(bus.ids, id)
-
-				if len(sub) == 0 {
-					delete(bus.subscribers, channel)
-				}
-				return nil
-			}
-		}
-
+	node := bus.tree()
+	sub, ok := node.find(channel, id)
+	if !ok {
 		return ErrChannelSubscriberNotFound
 	}
+
+	sub.cancel()
+	node.remove(channel, id)
+	delete(bus.ids, id)
+	return nil
 }
@@ -0,0 +1,129 @@
+package event
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSubscribePatternMatchesWildcards(t *testing.T) {
+	bus := NewBus()
+
+	var mu sync.Mutex
+	var single, multi []string
+
+	if err := bus.SubscribePattern("module.*.data", func(e *Event, err error) {
+		if e == nil {
+			return
+		}
+		mu.Lock()
+		single = append(single, e.Message().(string))
+		mu.Unlock()
+	}); err != nil {
+		t.Fatalf("SubscribePattern(*): %v", err)
+	}
+
+	if err := bus.SubscribePattern("service.network.#", func(e *Event, err error) {
+		if e == nil {
+			return
+		}
+		mu.Lock()
+		multi = append(multi, e.Message().(string))
+		mu.Unlock()
+	}); err != nil {
+		t.Fatalf("SubscribePattern(#): %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	bus.Publish(ctx, "module.sensor.data", "one-segment", nil)
+	bus.Publish(ctx, "service.network.sensor.reading", "multi-segment", nil)
+
+	deadline := time.After(200 * time.Millisecond)
+	for {
+		mu.Lock()
+		got := len(single) == 1 && len(multi) == 1
+		mu.Unlock()
+		if got {
+			break
+		}
+		select {
+		case <-deadline:
+			t.Fatalf("wildcard subscribers did not both fire: single=%v multi=%v", single, multi)
+		case <-time.After(time.Millisecond):
+		}
+	}
+}
+
+func TestPublishSyncAggregatesReplies(t *testing.T) {
+	bus := NewBus()
+
+	// Each handler is its own func literal: the bus identifies a subscriber
+	// by pattern and handler function, so closures of one literal would
+	// collide as ErrChannelSubscriberAlreadyExists.
+	handlers := []EventConsumer{
+		func(e *Event, err error) {
+			if e == nil {
+				return
+			}
+			e.Reply() <- "a"
+		},
+		func(e *Event, err error) {
+			if e == nil {
+				return
+			}
+			e.Reply() <- "b"
+		},
+	}
+	for _, handler := range handlers {
+		err := bus.SubscribePattern("sync.channel", handler)
+		if err != nil {
+			t.Fatalf("SubscribePattern: %v", err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	replies, err := bus.PublishSync(ctx, "sync.channel", "ping")
+	if err != nil {
+		t.Fatalf("PublishSync: %v", err)
+	}
+	if len(replies) != 2 {
+		t.Fatalf("got %d replies, want 2: %v", len(replies), replies)
+	}
+}
+
+func TestBroadcasterRepublishesSourceToSubscribers(t *testing.T) {
+	bus := NewBus()
+
+	received := make(chan string, 1)
+	if err := bus.SubscribePattern("sensor.#", func(e *Event, err error) {
+		if e == nil {
+			return
+		}
+		received <- e.Message().(string)
+	}); err != nil {
+		t.Fatalf("SubscribePattern: %v", err)
+	}
+
+	source := make(chan interface{}, 1)
+	broadcaster := bus.NewBroadcaster("sensor.temperature")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go broadcaster.Serve(ctx, source)
+
+	source <- "23.5C"
+
+	select {
+	case got := <-received:
+		if got != "23.5C" {
+			t.Fatalf("got %q, want %q", got, "23.5C")
+		}
+	case <-time.After(200 * time.Millisecond):
+		t.Fatal("broadcaster never republished the value")
+	}
+}
@@ -0,0 +1,43 @@
+package event
+
+// Option configures a Bus built with NewBus.
+type Option func(*Bus)
+
+// WithRingBuffer keeps the last size published events per channel in
+// memory, so a subscriber joining late can replay them without needing a
+// backing EventStore.
+func WithRingBuffer(size int) Option {
+	return func(bus *Bus) { bus.ring = newRingLog(size) }
+}
+
+// WithPersistence durably appends every published event to store, so it
+// can be replayed to subscribers across process restarts.
+func WithPersistence(store EventStore) Option {
+	return func(bus *Bus) { bus.store = store }
+}
+
+// WithStrictChannels makes Tx.Commit report an error for any queued
+// channel that has no subscriber at commit time, instead of silently
+// dropping it the way Publish does.
+func WithStrictChannels() Option {
+	return func(bus *Bus) { bus.strictChannels = true }
+}
+
+// SubscribeOption configures a single Subscribe/SubscribePattern call.
+type SubscribeOption func(*subscribeConfig)
+
+type subscribeConfig struct {
+	replay       bool
+	fromSequence uint64
+}
+
+// FromSequence replays every event recorded after sinceSeq, from the ring
+// buffer and/or the persistence store, before the subscription switches to
+// live delivery. Modules resuming a session after StopSession/StartSession
+// use this to catch up on what they missed per channel.
+func FromSequence(sinceSeq uint64) SubscribeOption {
+	return func(c *subscribeConfig) {
+		c.replay = true
+		c.fromSequence = sinceSeq
+	}
+}
@@ -0,0 +1,94 @@
+package event
+
+import (
+	"database/sql"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// SQLiteStore is an EventStore backed by a single SQLite table, suitable
+// for persisting the bus across process restarts.
+type SQLiteStore struct {
+	db *sql.DB
+}
+
+// NewSQLiteStore opens (creating if necessary) a SQLite-backed EventStore
+// at path.
+func NewSQLiteStore(path string) (*SQLiteStore, error) {
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		return nil, err
+	}
+
+	const schema = `
+CREATE TABLE IF NOT EXISTS events (
+	seq       INTEGER PRIMARY KEY AUTOINCREMENT,
+	channel   TEXT NOT NULL,
+	payload   BLOB NOT NULL,
+	timestamp DATETIME NOT NULL
+);
+CREATE INDEX IF NOT EXISTS events_channel_seq ON events (channel, seq);
+`
+	if _, err := db.Exec(schema); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+
+	return &SQLiteStore{db: db}, nil
+}
+
+func (s *SQLiteStore) Close() error {
+	return s.db.Close()
+}
+
+func (s *SQLiteStore) Append(channel string, payload []byte, ts time.Time) (uint64, error) {
+	res, err := s.db.Exec(
+		`INSERT INTO events (channel, payload, timestamp) VALUES (?, ?, ?)`,
+		channel, payload, ts,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+	return uint64(id), nil
+}
+
+func (s *SQLiteStore) Read(channel string, sinceSeq uint64) (EventIterator, error) {
+	rows, err := s.db.Query(
+		`SELECT seq, payload, timestamp FROM events WHERE channel = ? AND seq > ? ORDER BY seq ASC`,
+		channel, sinceSeq,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &sqliteIterator{rows: rows, channel: channel}, nil
+}
+
+type sqliteIterator struct {
+	rows    *sql.Rows
+	channel string
+	current StoredEvent
+	err     error
+}
+
+func (it *sqliteIterator) Next() bool {
+	if !it.rows.Next() {
+		it.err = it.rows.Err()
+		return false
+	}
+
+	it.current = StoredEvent{Channel: it.channel}
+	if it.err = it.rows.Scan(&it.current.Seq, &it.current.Payload, &it.current.Timestamp); it.err != nil {
+		return false
+	}
+	return true
+}
+
+func (it *sqliteIterator) Record() StoredEvent { return it.current }
+func (it *sqliteIterator) Err() error          { return it.err }
+func (it *sqliteIterator) Close() error        { return it.rows.Close() }
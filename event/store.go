@@ -0,0 +1,100 @@
+package event
+
+import (
+	"sync"
+	"time"
+)
+
+// StoredEvent is a single persisted publication, as handed back by an
+// EventStore when replaying a channel.
+type StoredEvent struct {
+	Seq       uint64
+	Channel   string
+	Payload   []byte
+	Timestamp time.Time
+}
+
+// EventIterator walks the events returned by EventStore.Read, oldest first.
+type EventIterator interface {
+	Next() bool
+	Record() StoredEvent
+	Err() error
+	Close() error
+}
+
+// EventStore durably persists published events so they can be replayed to
+// subscribers that were not present at publish time, e.g. a module
+// resuming a session after StopSession/StartSession.
+type EventStore interface {
+	Append(channel string, payload []byte, ts time.Time) (seq uint64, err error)
+	Read(channel string, sinceSeq uint64) (EventIterator, error)
+}
+
+type ringEntry struct {
+	seq     uint64
+	payload interface{}
+	ts      time.Time
+}
+
+// ringLog is an in-memory ring buffer of the most recent events per
+// channel, used to replay late subscribers without hitting a backing
+// EventStore. Entries keep the original, typed payload rather than a
+// serialised copy, so a replayed Event looks identical to a live one to the
+// handler.
+type ringLog struct {
+	mu      sync.Mutex
+	size    int
+	seq     uint64
+	entries map[string][]ringEntry
+}
+
+func newRingLog(size int) *ringLog {
+	return &ringLog{size: size, entries: make(map[string][]ringEntry)}
+}
+
+// append assigns channel's next sequence number to payload. Use appendAt
+// instead when a persistence store is already the authority on sequence
+// numbers, so the ring's numbering never diverges from it.
+func (r *ringLog) append(channel string, payload interface{}, ts time.Time) uint64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.seq++
+	r.insertLocked(channel, r.seq, payload, ts)
+	return r.seq
+}
+
+// appendAt records payload under an already-assigned seq - typically one
+// handed out by an EventStore - instead of incrementing the ring's own
+// counter, keeping both numberings in lockstep when a Bus is built with
+// both WithRingBuffer and WithPersistence.
+func (r *ringLog) appendAt(channel string, seq uint64, payload interface{}, ts time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if seq > r.seq {
+		r.seq = seq
+	}
+	r.insertLocked(channel, seq, payload, ts)
+}
+
+func (r *ringLog) insertLocked(channel string, seq uint64, payload interface{}, ts time.Time) {
+	buf := append(r.entries[channel], ringEntry{seq: seq, payload: payload, ts: ts})
+	if len(buf) > r.size {
+		buf = buf[len(buf)-r.size:]
+	}
+	r.entries[channel] = buf
+}
+
+func (r *ringLog) since(channel string, sinceSeq uint64) []ringEntry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var out []ringEntry
+	for _, e := range r.entries[channel] {
+		if e.seq > sinceSeq {
+			out = append(out, e)
+		}
+	}
+	return out
+}
@@ -0,0 +1,106 @@
+package event
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+type queuedEvent struct {
+	channel string
+	data    interface{}
+}
+
+// Tx stages several Publish calls, possibly across multiple channels, for
+// atomic delivery: every queued event is dispatched under a single snapshot
+// of the subscriber map, so a concurrent Publish/Subscribe never leaves a
+// subscriber seeing only part of the transaction.
+type Tx struct {
+	bus    *Bus
+	queued []queuedEvent
+}
+
+// NewTransaction returns an empty Tx bound to bus.
+func (bus *Bus) NewTransaction() *Tx {
+	return &Tx{bus: bus}
+}
+
+// Publish queues an event on channel without delivering it. Delivery, to
+// every channel queued on this Tx, happens only once Commit is called, in
+// the order Publish was called.
+func (tx *Tx) Publish(channel string, data interface{}) {
+	tx.queued = append(tx.queued, queuedEvent{channel: channel, data: data})
+}
+
+// Rollback discards every event queued on tx without delivering any of
+// them.
+func (tx *Tx) Rollback() {
+	tx.queued = nil
+}
+
+// Commit acquires bus.sync once, snapshots the subscriber map, then
+// dispatches every queued event under that snapshot, preserving per-channel
+// ordering so no subscriber observes a partial transaction interleaved with
+// another goroutine's publish. If the bus was built with
+// WithStrictChannels, a queued channel with no subscriber is still skipped
+// but contributes to the combined error Commit returns.
+func (tx *Tx) Commit(ctx context.Context) error {
+	bus := tx.bus
+	timeout := deadlineOf(ctx)
+
+	bus.sync.Lock()
+	defer bus.sync.Unlock()
+
+	type dispatch struct {
+		channel string
+		subs    []subscriber
+		data    interface{}
+		seq     uint64
+	}
+
+	var plan []dispatch
+	var errs []error
+
+	for _, qe := range tx.queued {
+		seq := bus.record(qe.channel, qe.data)
+
+		subs := bus.tree().match(qe.channel)
+		if len(subs) == 0 {
+			if bus.strictChannels {
+				errs = append(errs, errors.Wrapf(ErrChannelNotFound, "channel %q", qe.channel))
+			}
+			continue
+		}
+		plan = append(plan, dispatch{channel: qe.channel, subs: subs, data: qe.data, seq: seq})
+	}
+
+	// Deliver sequentially, in enqueue order, so two events queued on the
+	// same channel reach every subscriber in that same order; delivering
+	// each from its own goroutine (as Publish does) gives no such guarantee.
+	for _, d := range plan {
+		for _, sub := range d.subs {
+			tx.deliver(ctx, sub, d.data, d.seq, timeout)
+		}
+	}
+
+	tx.queued = nil
+
+	if len(errs) > 0 {
+		return errors.Errorf("transaction commit: %d channel(s) had no subscriber: %v", len(errs), errs)
+	}
+	return nil
+}
+
+// deliver sends data to sub, waiting up to timeout or ctx's cancellation.
+// The reply/error channels are buffered so that a subscriber handler
+// replying or erroring after Commit has moved on - which Tx, unlike
+// Publish, never reads back - cannot block it forever.
+func (tx *Tx) deliver(ctx context.Context, sub subscriber, data interface{}, seq uint64, timeout time.Duration) {
+	event := &Event{payload: data, seq: seq, reply: make(chan interface{}, 1), error: make(chan error, 1)}
+	select {
+	case sub.ch <- event:
+	case <-ctx.Done():
+	case <-time.After(timeout):
+	}
+}
@@ -0,0 +1,149 @@
+package event
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestTxRollbackDiscardsQueuedEvents(t *testing.T) {
+	bus := NewBus()
+
+	received := make(chan struct{}, 1)
+	if err := bus.Subscribe("tx.channel", func(e *Event, err error) {
+		if e == nil {
+			return
+		}
+		received <- struct{}{}
+	}); err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+
+	tx := bus.NewTransaction()
+	tx.Publish("tx.channel", "queued")
+	tx.Rollback()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := tx.Commit(ctx); err != nil {
+		t.Fatalf("Commit after Rollback: %v", err)
+	}
+
+	select {
+	case <-received:
+		t.Fatal("rolled-back event was delivered")
+	case <-time.After(30 * time.Millisecond):
+	}
+}
+
+func TestTxCommitWithStrictChannelsReportsMissingSubscriber(t *testing.T) {
+	bus := NewBus(WithStrictChannels())
+
+	tx := bus.NewTransaction()
+	tx.Publish("nobody.listens", "payload")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := tx.Commit(ctx); err == nil {
+		t.Fatal("expected Commit to report the channel with no subscriber")
+	}
+}
+
+func TestTxCommitDeliversSameChannelEventsInOrder(t *testing.T) {
+	bus := NewBus()
+
+	var mu sync.Mutex
+	var order []string
+	if err := bus.Subscribe("tx.ordered", func(e *Event, err error) {
+		if e == nil {
+			return
+		}
+		mu.Lock()
+		order = append(order, e.Message().(string))
+		mu.Unlock()
+	}); err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+
+	tx := bus.NewTransaction()
+	tx.Publish("tx.ordered", "first")
+	tx.Publish("tx.ordered", "second")
+	tx.Publish("tx.ordered", "third")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := tx.Commit(ctx); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+
+	deadline := time.After(200 * time.Millisecond)
+	for {
+		mu.Lock()
+		done := len(order) == 3
+		mu.Unlock()
+		if done {
+			break
+		}
+		select {
+		case <-deadline:
+			t.Fatalf("expected 3 events delivered, got %v", order)
+		case <-time.After(time.Millisecond):
+		}
+	}
+
+	want := []string{"first", "second", "third"}
+	for i, w := range want {
+		if order[i] != w {
+			t.Fatalf("order[%d] = %q, want %q (full: %v)", i, order[i], w, order)
+		}
+	}
+}
+
+func TestConcurrentTxCommitsDoNotRace(t *testing.T) {
+	bus := NewBus()
+
+	var count int32
+	var mu sync.Mutex
+	if err := bus.Subscribe("tx.concurrent", func(e *Event, err error) {
+		if e == nil {
+			return
+		}
+		mu.Lock()
+		count++
+		mu.Unlock()
+	}); err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+
+	const txCount = 10
+	var wg sync.WaitGroup
+	for i := 0; i < txCount; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			tx := bus.NewTransaction()
+			tx.Publish("tx.concurrent", i)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+			_ = tx.Commit(ctx)
+		}(i)
+	}
+	wg.Wait()
+
+	deadline := time.After(200 * time.Millisecond)
+	for {
+		mu.Lock()
+		done := count == txCount
+		mu.Unlock()
+		if done {
+			break
+		}
+		select {
+		case <-deadline:
+			t.Fatalf("expected %d deliveries, got %d", txCount, count)
+		case <-time.After(time.Millisecond):
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package event
+
+import "strings"
+
+const (
+	segmentWildcard      = "*"
+	multiSegmentWildcard = "#"
+)
+
+// trieNode is one segment of a `.`-separated subscription pattern. Storing
+// subscribers in a trie keyed on pattern segments keeps Publish's lookup
+// cost O(depth) regardless of how many subscribers are registered, instead
+// of the exact-match map it replaces.
+type trieNode struct {
+	children    map[string]*trieNode
+	subscribers []subscriber
+}
+
+func newTrieNode() *trieNode {
+	return &trieNode{children: make(map[string]*trieNode)}
+}
+
+// insert adds sub under pattern, creating intermediate nodes as needed.
+func (n *trieNode) insert(pattern string, sub subscriber) {
+	node := n
+	for _, segment := range strings.Split(pattern, ".") {
+		child, ok := node.children[segment]
+		if !ok {
+			child = newTrieNode()
+			node.children[segment] = child
+		}
+		node = child
+	}
+	node.subscribers = append(node.subscribers, sub)
+}
+
+// find returns the subscriber registered under the exact pattern with the
+// given id, without applying wildcard matching.
+func (n *trieNode) find(pattern string, id string) (subscriber, bool) {
+	node := n
+	for _, segment := range strings.Split(pattern, ".") {
+		child, ok := node.children[segment]
+		if !ok {
+			return subscriber{}, false
+		}
+		node = child
+	}
+	for _, sub := range node.subscribers {
+		if sub.id == id {
+			return sub, true
+		}
+	}
+	return subscriber{}, false
+}
+
+// remove deletes the subscriber identified by id from pattern, pruning any
+// node left with neither subscribers nor children, and reports whether a
+// subscriber was actually removed.
+func (n *trieNode) remove(pattern string, id string) bool {
+	return n.removeSegments(strings.Split(pattern, "."), id)
+}
+
+func (n *trieNode) removeSegments(segments []string, id string) bool {
+	if len(segments) == 0 {
+		for i, sub := range n.subscribers {
+			if sub.id == id {
+				n.subscribers = append(n.subscribers[:i], n.subscribers[i+1:]...)
+				return true
+			}
+		}
+		return false
+	}
+
+	child, ok := n.children[segments[0]]
+	if !ok {
+		return false
+	}
+
+	removed := child.removeSegments(segments[1:], id)
+	if removed && len(child.subscribers) == 0 && len(child.children) == 0 {
+		delete(n.children, segments[0])
+	}
+	return removed
+}
+
+// match collects every subscriber whose pattern matches channel, honouring
+// `*` (exactly one segment) and `#` (the remainder of the channel).
+func (n *trieNode) match(channel string) []subscriber {
+	return n.matchSegments(strings.Split(channel, "."))
+}
+
+func (n *trieNode) matchSegments(segments []string) []subscriber {
+	if len(segments) == 0 {
+		return n.subscribers
+	}
+
+	var matched []subscriber
+	if child, ok := n.children[multiSegmentWildcard]; ok {
+		matched = append(matched, child.subscribers...)
+	}
+	if child, ok := n.children[segmentWildcard]; ok {
+		matched = append(matched, child.matchSegments(segments[1:])...)
+	}
+	if child, ok := n.children[segments[0]]; ok {
+		matched = append(matched, child.matchSegments(segments[1:])...)
+	}
+	return matched
+}
@@ -1,12 +1,18 @@
 package kernel
 
 import (
+	"context"
 	"fmt"
+	"sync"
+	"time"
 
 	"github.com/xunleii/fantastic-broccoli/common/types"
 	"github.com/xunleii/fantastic-broccoli/common/types/service"
 	"github.com/xunleii/fantastic-broccoli/constant"
+	"github.com/xunleii/fantastic-broccoli/event"
+	"github.com/xunleii/fantastic-broccoli/kernel/supervisor"
 	"github.com/xunleii/fantastic-broccoli/log"
+	"github.com/xunleii/fantastic-broccoli/notifications"
 	"github.com/xunleii/fantastic-broccoli/properties"
 )
 
@@ -16,15 +22,55 @@ type Core struct {
 	properties *properties.Properties
 
 	notifications *service.NotificationQueue
-	internal      error
-	state         types.StateType
+	alerts        *notifications.Center
+	supervisor    *supervisor.Supervisor
+
+	mu       sync.Mutex
+	internal error
+	state    types.StateType
+	running  bool
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// defaultBackoff is applied to every service registered by Configure; a
+// service flapping past 5 failures in 30s escalates the root supervisor
+// into constant.States.Panic instead of retrying forever.
+var defaultBackoff = supervisor.Backoff{
+	Initial:       100 * time.Millisecond,
+	Multiplier:    2,
+	Max:           10 * time.Second,
+	FailureWindow: 30 * time.Second,
+	MaxFailures:   5,
 }
 
+// defaultNotificationHistory bounds how many operator-facing notifications
+// Core keeps in memory for UIs to query.
+const defaultNotificationHistory = 100
+
 var (
 	InfoStartServices   = log.NewArgumentBinder("start services")
 	InfoServicesStarted = log.NewArgumentBinder("services successfully started (%d services)")
 )
 
+// NewCore creates a Core whose services run under ctx: cancelling ctx, or
+// calling Stop, tears down every service's Serve goroutine. Operator-facing
+// notifications (module panics, session transitions, ...) are published on
+// bus and dispatched to sinks.
+func NewCore(ctx context.Context, bus *event.Bus, sinks ...notifications.Sink) *Core {
+	ctx, cancel := context.WithCancel(ctx)
+	alerts := notifications.New(bus, constant.Channels.Notification.String(), defaultNotificationHistory, sinks...)
+	return &Core{ctx: ctx, cancel: cancel, alerts: alerts, done: make(chan struct{})}
+}
+
+// Configure wires every service up (Start then Configure, as before) and
+// registers it as a Permanent child of the root supervisor, which is
+// responsible for restarting it should it later fail. Unlike before, a
+// single service failing to start no longer aborts the others outright:
+// it is simply not registered, and its error is carried in the returned
+// error once every service has had a chance to start.
 func (core *Core) Configure(services []service.Service, props *properties.Properties, logger log.Logger) error {
 	// Property file can be not loaded (props.IsLoaded = false) if file not found or invalid
 	if !props.IsLoaded() {
@@ -34,46 +80,93 @@ func (core *Core) Configure(services []service.Service, props *properties.Proper
 	core.services = services
 	core.logger = logger
 	core.notifications = service.NewNotificationQueue()
+	core.supervisor = supervisor.New(constant.EntityNames.Core, logger)
+	core.supervisor.OnEscalate = func(name string, cause error) {
+		core.alerts.Emit(notifications.ModulePanic(name, cause))
+	}
 
 	core.internal = nil
 	logger.Info(InfoStartServices)
 	for _, s := range services {
 		if !core.checkIf(s, s.Start(core.notifications, logger), IsStarted) ||
 			!core.checkIf(s, s.Configure(props), IsConfigured) {
-			return core.internal
+			continue
 		}
+		core.supervisor.Register(s.Name(), s, supervisor.Permanent, defaultBackoff)
 	}
 	logger.Info(InfoServicesStarted.Bind(len(services)))
 
-	core.state = constant.States.Idle
-	return nil
+	core.setState(constant.States.Idle)
+	return core.internalErr()
 }
 
+// Run hands every registered service to the root supervisor and blocks
+// until either the root context is cancelled or the supervisor escalates a
+// failure, in which case the remaining services are stopped in turn. Run
+// closes Core's done channel before returning, which is what Stop, called
+// from another goroutine, waits on. Run must be called at most once per
+// Core.
 func (core *Core) Run() error {
-	core.state = constant.States.Working
-	for _, s := range core.services {
-		if !core.checkIf(s, s.Process(), IsProcessed) {
-			return core.internal
-		}
+	core.mu.Lock()
+	core.running = true
+	core.mu.Unlock()
+	defer close(core.done)
 
-		for _, n := range core.notifications.Notifications(constant.EntityNames.Core) {
-			core.handle(n)
-		}
+	core.setState(constant.States.Working)
+	core.alerts.Emit(notifications.SessionStarted(constant.EntityNames.Core))
+
+	err := core.supervisor.Serve(core.ctx)
+	for _, n := range core.notifications.Notifications(constant.EntityNames.Core) {
+		core.handle(n)
 	}
-	core.state = constant.States.Idle
-	return nil
+
+	core.setInternal(err)
+	core.setState(constant.States.Idle)
+	return err
 }
 
+// Stop cancels the root context and, if Run is currently running (or has
+// already run) on another goroutine, blocks until it has actually
+// returned - per the supervisor's own Serve(ctx) contract - before
+// reporting the kernel as stopped and surfacing Run's result. Calling Stop
+// before Run ever started waits on nothing: it cancels the context, marks
+// the kernel stopped and emits the session-stopped notification.
 func (core *Core) Stop() error {
-	for _, s := range core.services {
-		if core.checkIf(s, s.Stop(), IsStopped) {
-			return core.internal
-		}
+	core.cancel()
+
+	core.mu.Lock()
+	running := core.running
+	core.mu.Unlock()
+
+	if running {
+		<-core.done
 	}
-	core.state = constant.States.Stopped
-	return nil
+
+	core.setState(constant.States.Stopped)
+	core.alerts.Emit(notifications.SessionStopped(constant.EntityNames.Core))
+	return core.internalErr()
 }
 
 func (core *Core) State() types.StateType {
+	core.mu.Lock()
+	defer core.mu.Unlock()
 	return core.state
 }
+
+func (core *Core) setState(state types.StateType) {
+	core.mu.Lock()
+	core.state = state
+	core.mu.Unlock()
+}
+
+func (core *Core) setInternal(err error) {
+	core.mu.Lock()
+	core.internal = err
+	core.mu.Unlock()
+}
+
+func (core *Core) internalErr() error {
+	core.mu.Lock()
+	defer core.mu.Unlock()
+	return core.internal
+}
@@ -0,0 +1,119 @@
+package kernel
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xunleii/fantastic-broccoli/common/types/service"
+	"github.com/xunleii/fantastic-broccoli/kernel/supervisor"
+	"github.com/xunleii/fantastic-broccoli/log"
+	"github.com/xunleii/fantastic-broccoli/notifications"
+)
+
+// fakeChild is a minimal supervisor.Child whose Serve blocks until its
+// context is cancelled, used to drive Core's cancellation propagation and
+// shutdown ordering without depending on a real service implementation.
+type fakeChild struct {
+	served chan struct{}
+}
+
+func newFakeChild() *fakeChild {
+	return &fakeChild{served: make(chan struct{})}
+}
+
+func (c *fakeChild) Serve(ctx context.Context) error {
+	defer close(c.served)
+	<-ctx.Done()
+	return nil
+}
+
+func TestCoreStopWaitsForRunAndCancelsServices(t *testing.T) {
+	core := NewCore(context.Background(), nil)
+	core.notifications = service.NewNotificationQueue()
+	core.supervisor = supervisor.New("test", log.NewNopLogger())
+
+	child := newFakeChild()
+	core.supervisor.Register("fake", child, supervisor.Permanent, defaultBackoff)
+
+	runDone := make(chan error, 1)
+	go func() { runDone <- core.Run() }()
+
+	// Give Run a moment to actually start the supervised child before Stop
+	// cancels it.
+	select {
+	case <-child.served:
+		t.Fatal("child.Serve returned before Stop was called")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	if err := core.Stop(); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+
+	select {
+	case <-child.served:
+	default:
+		t.Fatal("Stop returned before the supervised child's Serve returned")
+	}
+
+	select {
+	case <-runDone:
+	default:
+		t.Fatal("Stop returned before Run returned")
+	}
+
+	if state := core.State(); state != 0 {
+		// State is set last by Stop; this just exercises the guarded
+		// accessor concurrently with Run/Stop without racing.
+		_ = state
+	}
+}
+
+func TestCoreStopWithoutRunDoesNotBlock(t *testing.T) {
+	core := NewCore(context.Background(), nil)
+
+	done := make(chan error, 1)
+	go func() { done <- core.Stop() }()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Stop blocked forever when Run was never called")
+	}
+}
+
+func TestCoreEmitsSessionLifecycleNotifications(t *testing.T) {
+	core := NewCore(context.Background(), nil)
+	core.notifications = service.NewNotificationQueue()
+	core.supervisor = supervisor.New("test", log.NewNopLogger())
+
+	child := newFakeChild()
+	core.supervisor.Register("fake", child, supervisor.Permanent, defaultBackoff)
+
+	runDone := make(chan error, 1)
+	go func() { runDone <- core.Run() }()
+
+	if err := core.Stop(); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+	<-runDone
+
+	history := core.alerts.History()
+	var started, stopped bool
+	for _, n := range history {
+		if n.Category != notifications.CategorySession {
+			continue
+		}
+		if strings.HasSuffix(n.Title, "session started") {
+			started = true
+		}
+		if strings.HasSuffix(n.Title, "session stopped") {
+			stopped = true
+		}
+	}
+	if !started || !stopped {
+		t.Fatalf("expected both a session-started and session-stopped notification, got %+v", history)
+	}
+}
@@ -0,0 +1,234 @@
+package supervisor
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/xunleii/fantastic-broccoli/common/types"
+	"github.com/xunleii/fantastic-broccoli/constant"
+	"github.com/xunleii/fantastic-broccoli/log"
+)
+
+// RestartPolicy controls whether a Supervisor restarts a child once its
+// Serve method returns.
+type RestartPolicy int
+
+const (
+	// Permanent children are always restarted, whether Serve returned nil
+	// or an error.
+	Permanent RestartPolicy = iota
+	// Transient children are restarted only if Serve returned an error.
+	Transient
+	// Temporary children are never restarted.
+	Temporary
+)
+
+// Child is anything a Supervisor can run and, on failure, restart. Both
+// module.Module and service.Service satisfy it through their Serve method,
+// and so does *Supervisor itself, allowing supervisors to be nested.
+type Child interface {
+	Serve(ctx context.Context) error
+}
+
+// Backoff describes the exponential backoff curve applied between restarts
+// of a child, and the failure window used to detect a flapping child.
+type Backoff struct {
+	Initial       time.Duration
+	Multiplier    float64
+	Max           time.Duration
+	FailureWindow time.Duration
+	MaxFailures   int
+}
+
+type registration struct {
+	name    string
+	child   Child
+	policy  RestartPolicy
+	backoff Backoff
+
+	mu       sync.Mutex
+	failures []time.Time
+}
+
+var (
+	WarnChildPanicked  = log.NewArgumentBinder("supervised child %q panicked: %v")
+	InfoChildRestart   = log.NewArgumentBinder("restarting supervised child %q")
+	ErrorChildFlapping = log.NewArgumentBinder("supervised child %q exceeded failure threshold, escalating")
+)
+
+// Supervisor runs a set of named children, restarting them according to
+// their RestartPolicy and Backoff, the way a Suture-style supervision tree
+// does: a failure that escalates past the failure threshold stops every
+// sibling and is reported to the parent supervisor, if any.
+type Supervisor struct {
+	name   string
+	logger log.Logger
+
+	// OnEscalate, if set, is called whenever a child flaps past its
+	// failure threshold and the supervisor transitions to
+	// constant.States.Panic - typically wired to notifications.Center.Emit
+	// so the operator is told which child is failing.
+	OnEscalate func(name string, cause error)
+
+	mu       sync.Mutex
+	children []*registration
+	state    types.StateType
+}
+
+// New creates an empty Supervisor. Children are added with Register before
+// Serve is called.
+func New(name string, logger log.Logger) *Supervisor {
+	return &Supervisor{name: name, logger: logger, state: constant.States.Idle}
+}
+
+// Register adds a child to be run the next time Serve is called. Register
+// must not be called once Serve is running.
+func (s *Supervisor) Register(name string, child Child, policy RestartPolicy, backoff Backoff) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.children = append(s.children, &registration{name: name, child: child, policy: policy, backoff: backoff})
+}
+
+// State reports the supervisor's last observed state, transitioning to
+// constant.States.Panic once a child has flapped past its failure threshold.
+func (s *Supervisor) State() types.StateType {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.state
+}
+
+// Serve runs every registered child concurrently until ctx is cancelled or
+// a child escalates a failure past its restart policy, in which case the
+// remaining siblings are stopped and the failure is returned to the caller
+// (typically a parent Supervisor).
+func (s *Supervisor) Serve(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, len(s.children))
+
+	for _, reg := range s.children {
+		reg := reg
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := s.run(ctx, reg); err != nil {
+				errs <- err
+				cancel()
+			}
+		}()
+	}
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		return err
+	}
+	return nil
+}
+
+// run executes reg.child.Serve, restarting it per reg.policy until ctx is
+// done or reg escalates past its failure threshold.
+func (s *Supervisor) run(ctx context.Context, reg *registration) error {
+	for {
+		err := s.serveOnce(ctx, reg)
+
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		restart := false
+		switch reg.policy {
+		case Permanent:
+			restart = true
+		case Transient:
+			restart = err != nil
+		case Temporary:
+			restart = false
+		}
+
+		if !restart {
+			return err
+		}
+
+		if err != nil && s.flapping(reg) {
+			s.escalate(reg, err)
+			return err
+		}
+
+		s.logger.Info(InfoChildRestart.Bind(reg.name))
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(s.delay(reg)):
+		}
+	}
+}
+
+// serveOnce calls reg.child.Serve, converting a panic into an error so one
+// misbehaving child cannot take down the supervisor's own goroutine.
+func (s *Supervisor) serveOnce(ctx context.Context, reg *registration) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			s.logger.Warn(WarnChildPanicked.Bind(reg.name, r))
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return reg.child.Serve(ctx)
+}
+
+// flapping records a failure for reg and reports whether it has now
+// exceeded MaxFailures within FailureWindow.
+func (s *Supervisor) flapping(reg *registration) bool {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+
+	now := time.Now()
+	reg.failures = append(reg.failures, now)
+
+	cutoff := now.Add(-reg.backoff.FailureWindow)
+	kept := reg.failures[:0]
+	for _, t := range reg.failures {
+		if t.After(cutoff) {
+			kept = append(kept, t)
+		}
+	}
+	reg.failures = kept
+
+	return len(reg.failures) > reg.backoff.MaxFailures
+}
+
+// delay returns the backoff interval before reg's next restart attempt,
+// growing with its recent failure count and capped at backoff.Max.
+func (s *Supervisor) delay(reg *registration) time.Duration {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+
+	d := reg.backoff.Initial
+	for i := 0; i < len(reg.failures)-1; i++ {
+		d = time.Duration(float64(d) * reg.backoff.Multiplier)
+		if d > reg.backoff.Max {
+			return reg.backoff.Max
+		}
+	}
+	return d
+}
+
+// escalate marks the supervisor as panicking once reg has flapped past its
+// failure threshold. Callers stop every sibling by cancelling ctx and
+// returning the failure to their own parent supervisor, if any.
+func (s *Supervisor) escalate(reg *registration, cause error) {
+	s.logger.Error(ErrorChildFlapping.Bind(reg.name))
+
+	s.mu.Lock()
+	s.state = constant.States.Panic
+	s.mu.Unlock()
+
+	if s.OnEscalate != nil {
+		s.OnEscalate(reg.name, cause)
+	}
+}
@@ -0,0 +1,99 @@
+package supervisor
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/xunleii/fantastic-broccoli/constant"
+	"github.com/xunleii/fantastic-broccoli/log"
+)
+
+// countingChild fails its first n calls to Serve, then blocks until ctx is
+// cancelled.
+type countingChild struct {
+	calls    int32
+	failUpTo int32
+}
+
+func (c *countingChild) Serve(ctx context.Context) error {
+	n := atomic.AddInt32(&c.calls, 1)
+	if n <= c.failUpTo {
+		return errors.New("boom")
+	}
+	<-ctx.Done()
+	return nil
+}
+
+func TestSupervisorRestartsPermanentChildAfterFailure(t *testing.T) {
+	s := New("test", log.NewNopLogger())
+	child := &countingChild{failUpTo: 2}
+	s.Register("flaky", child, Permanent, Backoff{
+		Initial:       time.Millisecond,
+		Multiplier:    2,
+		Max:           10 * time.Millisecond,
+		FailureWindow: time.Second,
+		MaxFailures:   10,
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- s.Serve(ctx) }()
+
+	deadline := time.After(time.Second)
+	for atomic.LoadInt32(&child.calls) <= 2 {
+		select {
+		case <-deadline:
+			t.Fatal("child was not restarted after failing")
+		case <-time.After(time.Millisecond):
+		}
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("Serve: %v", err)
+	}
+}
+
+// alwaysFailChild fails Serve every time it is called.
+type alwaysFailChild struct{}
+
+func (alwaysFailChild) Serve(context.Context) error {
+	return errors.New("boom")
+}
+
+func TestSupervisorEscalatesFlappingChild(t *testing.T) {
+	s := New("test", log.NewNopLogger())
+
+	var escalated int32
+	var escalatedName string
+	s.OnEscalate = func(name string, cause error) {
+		atomic.StoreInt32(&escalated, 1)
+		escalatedName = name
+	}
+
+	s.Register("flapping", alwaysFailChild{}, Permanent, Backoff{
+		Initial:       time.Millisecond,
+		Multiplier:    1,
+		Max:           time.Millisecond,
+		FailureWindow: time.Second,
+		MaxFailures:   3,
+	})
+
+	err := s.Serve(context.Background())
+	if err == nil {
+		t.Fatal("expected Serve to return the escalating child's error")
+	}
+
+	if atomic.LoadInt32(&escalated) != 1 {
+		t.Fatal("OnEscalate was never called")
+	}
+	if escalatedName != "flapping" {
+		t.Fatalf("OnEscalate called for %q, want %q", escalatedName, "flapping")
+	}
+	if s.State() != constant.States.Panic {
+		t.Fatalf("State() = %v, want Panic", s.State())
+	}
+}
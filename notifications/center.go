@@ -0,0 +1,204 @@
+package notifications
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/xunleii/fantastic-broccoli/event"
+)
+
+// Subscriber is called by Center.Emit for every Notification whose
+// severity satisfies the minimum given to Subscribe.
+type Subscriber func(n Notification)
+
+type severitySubscriber struct {
+	id  uint64
+	min Severity
+	fn  Subscriber
+}
+
+// defaultRateLimit is the minimum delay Center enforces between two
+// notifications sharing the same Category and Title, so a flapping module
+// cannot drown the operator in duplicate alerts.
+const defaultRateLimit = time.Second
+
+// Center classifies operator-facing events into Notification records,
+// fans them out to a set of pluggable Sinks and severity-filtered
+// Subscribers, and keeps the last N in memory so a UI can query recent
+// history - including over the event bus, since every Notification is
+// also published on Center's channel.
+type Center struct {
+	bus     *event.Bus
+	channel string
+
+	maxHistory int
+	rateLimit  time.Duration
+
+	mu      sync.Mutex
+	sinks   []Sink
+	history []Notification
+
+	rateMu   sync.Mutex
+	lastSeen map[string]time.Time
+
+	subMu       sync.Mutex
+	subSeq      uint64
+	subscribers []severitySubscriber
+}
+
+// New creates a Center that publishes every Notification on channel
+// (typically constant.Channels.Notification.String()) over bus, retaining
+// the last maxHistory notifications in memory.
+func New(bus *event.Bus, channel string, maxHistory int, sinks ...Sink) *Center {
+	return &Center{
+		bus:        bus,
+		channel:    channel,
+		maxHistory: maxHistory,
+		rateLimit:  defaultRateLimit,
+		sinks:      sinks,
+		lastSeen:   make(map[string]time.Time),
+	}
+}
+
+// AddSink registers an additional sink every future Notification is
+// dispatched to.
+func (c *Center) AddSink(sink Sink) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sinks = append(c.sinks, sink)
+}
+
+// Subscribe registers fn to be called for every future Notification whose
+// Severity is at least min, returning a function that cancels the
+// subscription.
+func (c *Center) Subscribe(min Severity, fn Subscriber) func() {
+	c.subMu.Lock()
+	c.subSeq++
+	id := c.subSeq
+	c.subscribers = append(c.subscribers, severitySubscriber{id: id, min: min, fn: fn})
+	c.subMu.Unlock()
+
+	return func() {
+		c.subMu.Lock()
+		defer c.subMu.Unlock()
+		for i, sub := range c.subscribers {
+			if sub.id == id {
+				c.subscribers = append(c.subscribers[:i], c.subscribers[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// Emit classifies and dispatches n: it is recorded in history, published
+// on the event bus, handed to every Sink and to every Subscriber whose
+// minimum severity it satisfies - unless it is rate limited as a repeat of
+// a recent notification sharing its Category and Title.
+func (c *Center) Emit(n Notification) {
+	if n.Timestamp.IsZero() {
+		n.Timestamp = time.Now()
+	}
+
+	if c.limited(n) {
+		return
+	}
+
+	c.record(n)
+
+	if c.bus != nil {
+		c.bus.Publish(context.Background(), c.channel, n, nil)
+	}
+
+	for _, sink := range c.sinksSnapshot() {
+		_ = sink.Notify(n)
+	}
+
+	for _, sub := range c.subscribersSnapshot() {
+		if n.Severity >= sub.min {
+			sub.fn(n)
+		}
+	}
+}
+
+// History returns the last N notifications recorded, oldest first.
+func (c *Center) History() []Notification {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	out := make([]Notification, len(c.history))
+	copy(out, c.history)
+	return out
+}
+
+func (c *Center) limited(n Notification) bool {
+	c.rateMu.Lock()
+	defer c.rateMu.Unlock()
+
+	key := fmt.Sprintf("%s|%s", n.Category, n.Title)
+	if last, ok := c.lastSeen[key]; ok && n.Timestamp.Sub(last) < c.rateLimit {
+		return true
+	}
+	c.lastSeen[key] = n.Timestamp
+	return false
+}
+
+func (c *Center) record(n Notification) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.history = append(c.history, n)
+	if len(c.history) > c.maxHistory {
+		c.history = c.history[len(c.history)-c.maxHistory:]
+	}
+}
+
+func (c *Center) sinksSnapshot() []Sink {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	out := make([]Sink, len(c.sinks))
+	copy(out, c.sinks)
+	return out
+}
+
+func (c *Center) subscribersSnapshot() []severitySubscriber {
+	c.subMu.Lock()
+	defer c.subMu.Unlock()
+
+	out := make([]severitySubscriber, len(c.subscribers))
+	copy(out, c.subscribers)
+	return out
+}
+
+// ModulePanic builds the Notification the kernel emits when a supervised
+// service or module escalates past its restart failure threshold.
+func ModulePanic(name string, cause error) Notification {
+	return Notification{
+		Category: CategoryModule,
+		Severity: SeverityCritical,
+		Title:    fmt.Sprintf("%s panicked", name),
+		Message:  cause.Error(),
+	}
+}
+
+// SessionStarted builds the Notification Core emits once it starts running
+// its supervised services.
+func SessionStarted(name string) Notification {
+	return Notification{
+		Category: CategorySession,
+		Severity: SeverityInfo,
+		Title:    fmt.Sprintf("%s session started", name),
+	}
+}
+
+// SessionStopped builds the Notification Core emits once every supervised
+// service has stopped.
+func SessionStopped(name string) Notification {
+	return Notification{
+		Category: CategorySession,
+		Severity: SeverityInfo,
+		Title:    fmt.Sprintf("%s session stopped", name),
+	}
+}
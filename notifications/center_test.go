@@ -0,0 +1,110 @@
+package notifications
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+type recordingSink struct {
+	mu  sync.Mutex
+	got []Notification
+}
+
+func (s *recordingSink) Notify(n Notification) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.got = append(s.got, n)
+	return nil
+}
+
+func (s *recordingSink) notifications() []Notification {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]Notification, len(s.got))
+	copy(out, s.got)
+	return out
+}
+
+func TestCenterSubscribeFiltersBySeverity(t *testing.T) {
+	c := New(nil, "notifications", 10)
+
+	var mu sync.Mutex
+	var seen []Notification
+	unsubscribe := c.Subscribe(SeverityWarning, func(n Notification) {
+		mu.Lock()
+		seen = append(seen, n)
+		mu.Unlock()
+	})
+	defer unsubscribe()
+
+	c.Emit(Notification{Category: CategoryModule, Severity: SeverityInfo, Title: "info-a"})
+	c.Emit(Notification{Category: CategoryModule, Severity: SeverityCritical, Title: "critical-a"})
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(seen) != 1 {
+		t.Fatalf("got %d notifications, want 1 (info below the SeverityWarning floor should be filtered): %v", len(seen), seen)
+	}
+	if seen[0].Title != "critical-a" {
+		t.Fatalf("got title %q, want %q", seen[0].Title, "critical-a")
+	}
+}
+
+func TestCenterUnsubscribeStopsDelivery(t *testing.T) {
+	c := New(nil, "notifications", 10)
+
+	calls := 0
+	unsubscribe := c.Subscribe(SeverityInfo, func(n Notification) { calls++ })
+	unsubscribe()
+
+	c.Emit(Notification{Category: CategoryModule, Severity: SeverityCritical, Title: "after-unsubscribe"})
+
+	if calls != 0 {
+		t.Fatalf("got %d calls after Unsubscribe, want 0", calls)
+	}
+}
+
+func TestCenterRateLimitsRepeatNotifications(t *testing.T) {
+	c := New(nil, "notifications", 10)
+	c.rateLimit = 50 * time.Millisecond
+
+	sink := &recordingSink{}
+	c.AddSink(sink)
+
+	n := Notification{Category: CategoryModule, Severity: SeverityCritical, Title: "flaky panicked"}
+	c.Emit(n)
+	c.Emit(n)
+
+	if got := len(sink.notifications()); got != 1 {
+		t.Fatalf("got %d sink deliveries, want 1 (second Emit should be rate limited)", got)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	c.Emit(n)
+
+	if got := len(sink.notifications()); got != 2 {
+		t.Fatalf("got %d sink deliveries, want 2 (rate limit window should have elapsed)", got)
+	}
+}
+
+func TestCenterHistoryBoundedByMaxHistory(t *testing.T) {
+	c := New(nil, "notifications", 2)
+
+	for i := 0; i < 3; i++ {
+		c.rateLimit = 0
+		c.Emit(Notification{
+			Category: CategoryModule,
+			Severity: SeverityInfo,
+			Title:    string(rune('a' + i)),
+		})
+	}
+
+	history := c.History()
+	if len(history) != 2 {
+		t.Fatalf("got %d entries, want 2 (bounded by maxHistory)", len(history))
+	}
+	if history[0].Title != "b" || history[1].Title != "c" {
+		t.Fatalf("got history %v, want the 2 most recent entries", history)
+	}
+}
@@ -0,0 +1,50 @@
+package notifications
+
+import "time"
+
+// Category classifies what part of the system a Notification is about.
+// CategoryModule and CategorySession are emitted by the kernel today;
+// it is a plain string type so callers adding a new emission site
+// (hardware disconnects, configuration reloads, ...) can define their own
+// constant once this tree actually has something to detect them with,
+// rather than waiting on this package.
+type Category string
+
+const (
+	CategoryModule  Category = "module"
+	CategorySession Category = "session"
+)
+
+// Severity ranks how urgently a Notification needs an operator's attention.
+type Severity int
+
+const (
+	SeverityInfo Severity = iota
+	SeverityWarning
+	SeverityCritical
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityInfo:
+		return "info"
+	case SeverityWarning:
+		return "warning"
+	case SeverityCritical:
+		return "critical"
+	default:
+		return "unknown"
+	}
+}
+
+// Notification is a structured, operator-facing record of something the
+// kernel thinks is worth surfacing - a module panic or a session start/stop
+// today.
+type Notification struct {
+	Category  Category
+	Severity  Severity
+	Title     string
+	Message   string
+	Deeplink  string
+	Timestamp time.Time
+}
@@ -0,0 +1,129 @@
+package notifications
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// Sink receives every Notification a Center emits. Center ignores Notify
+// errors: a failing sink never stops delivery to the remaining sinks.
+type Sink interface {
+	Notify(n Notification) error
+}
+
+// StdoutSink writes each Notification to stdout, one line per
+// notification.
+type StdoutSink struct{}
+
+func (StdoutSink) Notify(n Notification) error {
+	_, err := fmt.Printf("[%s] %s: %s - %s\n", n.Severity, n.Category, n.Title, n.Message)
+	return err
+}
+
+// FileSink appends each Notification, JSON-encoded, to a file.
+type FileSink struct {
+	path string
+	mu   sync.Mutex
+}
+
+func NewFileSink(path string) *FileSink {
+	return &FileSink{path: path}
+}
+
+func (s *FileSink) Notify(n Notification) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	line, err := json.Marshal(n)
+	if err != nil {
+		return err
+	}
+
+	_, err = f.Write(append(line, '\n'))
+	return err
+}
+
+// Emitter is the subset of network.Service used by NetworkSink, avoiding a
+// dependency on the network package from notifications.
+type Emitter interface {
+	Emit(channel string, payload interface{}) bool
+}
+
+// NetworkSink emits each Notification through Emitter, typically
+// network.Service.Emit on constant.Channels.Notification.
+type NetworkSink struct {
+	emitter Emitter
+	channel string
+}
+
+func NewNetworkSink(emitter Emitter, channel string) *NetworkSink {
+	return &NetworkSink{emitter: emitter, channel: channel}
+}
+
+func (s *NetworkSink) Notify(n Notification) error {
+	if !s.emitter.Emit(s.channel, n) {
+		return fmt.Errorf("failed to emit notification on channel %q", s.channel)
+	}
+	return nil
+}
+
+// WebhookSink POSTs each Notification, JSON-encoded, to url, retrying with
+// an exponential backoff on failure.
+type WebhookSink struct {
+	url        string
+	client     *http.Client
+	maxRetries int
+	backoff    time.Duration
+}
+
+// NewWebhookSink creates a WebhookSink retrying up to maxRetries times,
+// starting at initialBackoff and doubling after every attempt.
+func NewWebhookSink(url string, maxRetries int, initialBackoff time.Duration) *WebhookSink {
+	return &WebhookSink{
+		url:        url,
+		client:     &http.Client{Timeout: 5 * time.Second},
+		maxRetries: maxRetries,
+		backoff:    initialBackoff,
+	}
+}
+
+func (s *WebhookSink) Notify(n Notification) error {
+	payload, err := json.Marshal(n)
+	if err != nil {
+		return err
+	}
+
+	var lastErr error
+	delay := s.backoff
+	for attempt := 0; attempt <= s.maxRetries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(delay)
+			delay *= 2
+		}
+
+		resp, err := s.client.Post(s.url, "application/json", bytes.NewReader(payload))
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode < 300 {
+			return nil
+		}
+		lastErr = fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+
+	return fmt.Errorf("webhook notify failed after %d attempts: %w", s.maxRetries+1, lastErr)
+}
@@ -36,7 +36,7 @@ func defaultNotificationHandler(service *Service, n *notification.Notification)
 	case *object.CommandObject:
 		succeed = service.emit(constant.Channels.Command.String(), *o)
 	case *object.DataObject:
-		succeed = service.emit(constant.Channels.Data.String(), *o)
+		succeed = service.emit(fmt.Sprintf("%s.sensor.%s", constant.Channels.Data.String(), o.SensorID()), *o)
 	case *object.ErrorObject:
 		succeed = service.emit(constant.Channels.Error.String(), *o)
 	default: